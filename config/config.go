@@ -4,51 +4,119 @@
 package config
 
 import (
+	"io"
 	"os"
-	"strconv"
-
-	"github.com/joho/godotenv"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Service holds application configuration.
 // It includes database, server, and telemetry configuration.
 type Service struct {
-	Name         string
-	dbEnv        DBConfig
-	redisEnv     RedisConfig
-	srvConfg     ServerConf
-	jaegerConfig JaegerConfig
+	Name            string
+	dbEnv           DBConfig
+	redisEnv        RedisConfig
+	srvConfg        ServerConf
+	jaegerConfig    JaegerConfig
+	telemetryConfig TelemetryConfig
+
+	// snap, loader, and sources are all replaced wholesale by each
+	// LoadConfig call rather than mutated in place, and read through an
+	// atomic.Pointer, so Get*Config/Validate/Dump/Source stay lock-free
+	// even while Watch is reloading them in the background.
+	snap    atomic.Pointer[Snapshot]
+	loader  atomic.Pointer[loader]
+	sources atomic.Pointer[map[string]string]
+
+	mu          sync.Mutex
+	subscribers []subscriber
+	reloadables []Reloadable
 }
 
 type DBConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	Name     string
+	Host     string `env:"DB_HOST" default:"localhost"`
+	Port     string `env:"DB_PORT" default:"3306" validate:"min=1,max=65535"`
+	User     string `env:"DB_USER" default:"user"`
+	Password string `env:"DB_PASSWORD" required:"true" secret:"true"`
+	Name     string `env:"DB_NAME" default:"mydatabase"`
 }
 
+// RedisConfig holds configuration for connecting to Redis. It supports
+// single-node, Sentinel, and Cluster topologies via redis.UniversalClient
+// (see NewUniversalClient).
 type RedisConfig struct {
-	Host     string
-	Port     string
-	Password string
-	DB       int
+	// Mode selects the client topology: "single", "sentinel", or "cluster".
+	// NewUniversalClient uses it to pick the concrete client constructor
+	// explicitly, rather than inferring the topology from the shape of
+	// Addresses/MasterName the way redis.NewUniversalClient does.
+	Mode string `env:"REDIS_MODE" default:"single"`
+	// Addresses is the list of host:port pairs to connect to. In sentinel
+	// mode these are the Sentinel addresses; in cluster mode these are the
+	// cluster seed nodes; in single mode it holds exactly one entry. It is
+	// derived from REDIS_ADDRS (or Host/Port, or REDIS_URL) rather than
+	// tag-driven, since a comma-separated list doesn't map onto a single
+	// struct field; see parseRedisAddrs.
+	Addresses []string
+
+	// Host and Port back the single-node REDIS_HOST/REDIS_PORT pair used
+	// by earlier versions of this template. They're tagged (rather than
+	// read with getEnv, as before) purely so detectManagedURLs can consult
+	// the loader's origin for them and avoid clobbering a value the user
+	// set explicitly; Addresses is what callers should use.
+	Host string `env:"REDIS_HOST" default:"localhost"`
+	Port string `env:"REDIS_PORT" default:"6379" validate:"min=1,max=65535"`
+
+	// MasterName is the Sentinel master set name. Only used when Mode is
+	// "sentinel".
+	MasterName string `env:"REDIS_MASTER_NAME"`
+	// Username and Password authenticate against the Redis server/cluster
+	// itself (Redis 6+ ACLs). SentinelUsername/SentinelPassword are a
+	// separate, optional credential pair for the Sentinels themselves.
+	Username         string `env:"REDIS_USERNAME"`
+	Password         string `env:"REDIS_PASSWORD" required:"true" secret:"true"`
+	SentinelUsername string `env:"REDIS_SENTINEL_USERNAME"`
+	SentinelPassword string `env:"REDIS_SENTINEL_PASSWORD" secret:"true"`
+	DB               int    `env:"REDIS_DB" default:"0"`
+
+	// RouteByLatency and RouteRandomly control read replica routing in
+	// cluster mode.
+	RouteByLatency bool `env:"REDIS_ROUTE_BY_LATENCY"`
+	RouteRandomly  bool `env:"REDIS_ROUTE_RANDOMLY"`
+
+	PoolSize     int           `env:"REDIS_POOL_SIZE"`
+	MinIdleConns int           `env:"REDIS_MIN_IDLE_CONNS"`
+	ReadTimeout  time.Duration `env:"REDIS_READ_TIMEOUT"`
+	WriteTimeout time.Duration `env:"REDIS_WRITE_TIMEOUT"`
+	DialTimeout  time.Duration `env:"REDIS_DIAL_TIMEOUT"`
+
+	TLSEnabled  bool   `env:"REDIS_TLS_ENABLED"`
+	TLSCertFile string `env:"REDIS_TLS_CERT_FILE"`
+	TLSKeyFile  string `env:"REDIS_TLS_KEY_FILE"`
+	TLSCAFile   string `env:"REDIS_TLS_CA_FILE"`
 }
 
 type ServerConf struct {
-	Address string
-	Port    string
+	Address string `env:"SERVER_ADDR"`
+	Port    string `env:"SERVER_PORT" default:"8080" validate:"min=1,max=65535"`
 }
 
+// JaegerConfig carries the deprecated Jaeger agent UDP protocol settings.
+//
+// Deprecated: use TelemetryConfig, which exports traces over OTLP to any
+// modern OTel-compatible backend. This is kept as a fallback for one
+// release behind TelemetryConfig.LegacyJaeger.
 type JaegerConfig struct {
-	AgentHost string
-	AgentPort string
+	AgentHost string `env:"JAEGER_AGENT_HOST" default:"localhost"`
+	AgentPort string `env:"JAEGER_AGENT_PORT" default:"6831"`
 }
 
 func NewService() *Service {
-	return &Service{
+	cnf := &Service{
 		Name: "go-rest-api-template",
 	}
+	cnf.snap.Store(&Snapshot{})
+	return cnf
 }
 
 // Init initializes the application configuration by loading environment variables.
@@ -57,41 +125,95 @@ func (cnf *Service) Init() error {
 	return cnf.LoadConfig()
 }
 
-// LoadConfig loads configuration from environment variables
+// LoadConfig loads configuration from layered sources (CLI flags, env vars,
+// .env file, CONFIG_FILE, struct defaults - see loader) by reading the env,
+// default, required, secret, and validate tags on DBConfig, RedisConfig,
+// ServerConf, and JaegerConfig. Adding a new config field is therefore a
+// one-line struct change rather than an edit here.
 func (cnf *Service) LoadConfig() error {
-	// Load .env file if present (optional - env vars may be set by Docker, etc.)
-	_ = godotenv.Load()
-
-	cnf.dbEnv = DBConfig{
-		Port:     getEnv("DB_PORT", "3306"),
-		Host:     getEnv("DB_HOST", "localhost"),
-		User:     getEnv("DB_USER", "user"),
-		Password: getEnv("DB_PASSWORD", "password"),
-		Name:     getEnv("DB_NAME", "mydatabase"),
+	l := newLoader()
+	l.register(&cnf.dbEnv)
+	l.register(&cnf.redisEnv)
+	l.register(&cnf.srvConfg)
+	l.register(&cnf.jaegerConfig)
+	l.register(&cnf.telemetryConfig)
+	if err := l.parse(); err != nil {
+		return err
+	}
+
+	// Addresses is derived rather than tag-driven (see its doc comment).
+	cnf.redisEnv.Addresses = parseRedisAddrs(cnf.redisEnv.Host, cnf.redisEnv.Port)
+
+	// Fill in Redis/DB fields from a PaaS-injected connection URL (Heroku,
+	// Render, Fly, Railway, ...) when one is present. sources starts fresh
+	// each call so a platform var that's since been unset doesn't leave a
+	// stale attribution behind.
+	sources := make(map[string]string)
+	cnf.detectManagedURLs(l, sources)
+
+	// Headers and ResourceAttributes are "k=v,k2=v2" strings, so they're
+	// parsed rather than tag-driven (see TelemetryConfig).
+	cnf.telemetryConfig.Headers = parseKV(cnf.telemetryConfig.HeadersRaw)
+	cnf.telemetryConfig.ResourceAttributes = parseKV(cnf.telemetryConfig.ResourceAttributesRaw)
+	warnIfLegacyJaeger(cnf.telemetryConfig)
+
+	cnf.loader.Store(l)
+	cnf.sources.Store(&sources)
+	cnf.snap.Store(&Snapshot{
+		DB:        cnf.dbEnv,
+		Redis:     cnf.redisEnv,
+		Server:    cnf.srvConfg,
+		Jaeger:    cnf.jaegerConfig,
+		Telemetry: cnf.telemetryConfig,
+	})
+
+	return nil
+}
+
+// Validate checks every `required` and `validate` constraint declared on
+// the config structs, returning a ValidationErrors aggregating every
+// violation rather than stopping at the first one.
+func (cnf *Service) Validate() error {
+	l := cnf.loader.Load()
+	if l == nil {
+		return nil
 	}
+	return l.validate()
+}
 
-	// Redis config
-	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
-	cnf.redisEnv = RedisConfig{
-		Host:     getEnv("REDIS_HOST", "localhost"),
-		Port:     getEnv("REDIS_PORT", "6379"),
-		Password: getEnv("REDIS_PASSWORD", ""),
-		DB:       redisDB,
+// Dump writes the resolved configuration to w as
+// "ENV_KEY=value (source: origin)" lines, redacting fields tagged
+// secret:"true".
+func (cnf *Service) Dump(w io.Writer) {
+	l := cnf.loader.Load()
+	if l == nil {
+		return
 	}
+	l.dump(w)
+}
 
-	// Server config
-	cnf.srvConfg = ServerConf{
-		Address: getEnv("SERVER_ADDR", ""),
-		Port:    getEnv("SERVER_PORT", "8080"),
+// Source reports where a configuration field's value came from: an
+// explicit var (flag, environment, .env file, or CONFIG_FILE), a
+// PaaS-injected platform URL (e.g. "platform:REDISCLOUD_URL"), or
+// "default" when nothing overrode the struct's default tag. field is the
+// field's env tag, e.g. "DB_HOST" or "REDIS_PASSWORD".
+func (cnf *Service) Source(field string) string {
+	if sources := cnf.sources.Load(); sources != nil {
+		if origin, ok := (*sources)[field]; ok {
+			return origin
+		}
 	}
 
-	// Jaeger config
-	cnf.jaegerConfig = JaegerConfig{
-		AgentHost: getEnv("JAEGER_AGENT_HOST", "localhost"),
-		AgentPort: getEnv("JAEGER_AGENT_PORT", "6831"),
+	if l := cnf.loader.Load(); l != nil {
+		if origin, ok := l.origin(field); ok {
+			if origin == "default" {
+				return "default"
+			}
+			return "explicit:" + origin
+		}
 	}
 
-	return nil
+	return "unknown"
 }
 
 // getEnv gets an environment variable or returns a default value
@@ -102,22 +224,34 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
-// GetDBConfig returns the database configuration
+// GetDBConfig returns the database configuration from the current
+// Snapshot.
 func (cnf *Service) GetDBConfig() DBConfig {
-	return cnf.dbEnv
+	return cnf.snap.Load().DB
 }
 
-// GetRedisConfig returns the Redis configuration
+// GetRedisConfig returns the Redis configuration from the current
+// Snapshot.
 func (cnf *Service) GetRedisConfig() RedisConfig {
-	return cnf.redisEnv
+	return cnf.snap.Load().Redis
 }
 
-// GetServerConfig returns the server configuration
+// GetServerConfig returns the server configuration from the current
+// Snapshot.
 func (cnf *Service) GetServerConfig() ServerConf {
-	return cnf.srvConfg
+	return cnf.snap.Load().Server
 }
 
-// GetJaegerConfig returns the Jaeger configuration
+// GetJaegerConfig returns the deprecated Jaeger agent configuration from
+// the current Snapshot.
+//
+// Deprecated: use GetTelemetryConfig.
 func (cnf *Service) GetJaegerConfig() JaegerConfig {
-	return cnf.jaegerConfig
+	return cnf.snap.Load().Jaeger
+}
+
+// GetTelemetryConfig returns the OTLP telemetry configuration from the
+// current Snapshot.
+func (cnf *Service) GetTelemetryConfig() TelemetryConfig {
+	return cnf.snap.Load().Telemetry
 }
@@ -0,0 +1,325 @@
+package config
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// loader populates Go structs from layered configuration sources. A field
+// is described entirely by its struct tags:
+//
+//	env:"DB_PORT" default:"3306" required:"true" secret:"true" validate:"min=1,max=65535"
+//
+// Sources are consulted in order of precedence, highest first:
+//
+//	explicit CLI flags > environment variables > .env file > CONFIG_FILE (YAML/JSON) > the `default` tag
+//
+// so adding a new config field is a one-line struct change rather than
+// editing LoadConfig.
+type loader struct {
+	flags      *flag.FlagSet
+	flagValues map[string]*string
+	envFile    map[string]string
+	fileValues map[string]string
+
+	fields []*loadedField
+}
+
+// loadedField is a single tagged struct field bound to its source value,
+// kept around so Validate and Dump can inspect it after parse.
+type loadedField struct {
+	value    reflect.Value
+	envKey   string
+	origin   string
+	raw      string
+	required bool
+	secret   bool
+	validate string
+}
+
+// dotenvKeys holds the keys the last newLoader copied from the .env file
+// into the process env (those not already set there), so parse can report
+// them as "dotenv" rather than "env", and the next newLoader can drop them
+// before re-reading .env, so an edited or removed entry is not shadowed by
+// its stale copy.
+var dotenvKeys = make(map[string]bool)
+
+// newLoader reads the .env file and CONFIG_FILE (if set) once, up front, so
+// every registered field can be resolved against the same snapshot of
+// layered sources.
+func newLoader() *loader {
+	for key := range dotenvKeys {
+		os.Unsetenv(key)
+	}
+	dotenvKeys = make(map[string]bool)
+
+	envFile, _ := godotenv.Read(".env") // read-only, so real env vars can still be told apart from .env ones
+	// Also copy .env into the process env for non-tag-driven lookups
+	// (getEnv), without overriding a real env var.
+	for key, value := range envFile {
+		if _, exists := os.LookupEnv(key); !exists {
+			os.Setenv(key, value)
+			dotenvKeys[key] = true
+		}
+	}
+
+	l := &loader{
+		flags:      flag.NewFlagSet(os.Args[0], flag.ContinueOnError),
+		flagValues: make(map[string]*string),
+		envFile:    envFile,
+		fileValues: readConfigFile(getEnv("CONFIG_FILE", "")),
+	}
+	l.flags.SetOutput(io.Discard)
+
+	return l
+}
+
+// register walks the exported fields of dst (a pointer to a config struct)
+// and binds every field carrying an `env` tag, defining a matching CLI flag
+// for it. Call parse once all structs have been registered.
+func (l *loader) register(dst interface{}) {
+	v := reflect.ValueOf(dst).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		envKey, ok := sf.Tag.Lookup("env")
+		if !ok {
+			continue
+		}
+
+		flagName := strings.ToLower(strings.ReplaceAll(envKey, "_", "-"))
+		l.flagValues[envKey] = l.flags.String(flagName, "", fmt.Sprintf("overrides %s", envKey))
+
+		l.fields = append(l.fields, &loadedField{
+			value:    v.Field(i),
+			envKey:   envKey,
+			required: sf.Tag.Get("required") == "true",
+			secret:   sf.Tag.Get("secret") == "true",
+			validate: sf.Tag.Get("validate"),
+			raw:      sf.Tag.Get("default"),
+			origin:   "default",
+		})
+	}
+}
+
+// parse resolves every registered field against, in precedence order, CLI
+// flags, environment variables, the .env file, CONFIG_FILE, and finally the
+// field's default tag, then assigns it onto the destination struct.
+func (l *loader) parse() error {
+	_ = l.flags.Parse(os.Args[1:])
+
+	for _, f := range l.fields {
+		if flagVal := l.flagValues[f.envKey]; flagVal != nil && *flagVal != "" {
+			f.raw, f.origin = *flagVal, "flag"
+		} else if envVal, ok := os.LookupEnv(f.envKey); ok && !dotenvKeys[f.envKey] {
+			f.raw, f.origin = envVal, "env"
+		} else if dotVal, ok := l.envFile[f.envKey]; ok {
+			f.raw, f.origin = dotVal, "dotenv"
+		} else if fileVal, ok := l.fileValues[f.envKey]; ok {
+			f.raw, f.origin = fileVal, "file"
+		}
+
+		if err := setField(f.value, f.raw); err != nil {
+			return fmt.Errorf("config: %s: %w", f.envKey, err)
+		}
+	}
+
+	return nil
+}
+
+// origin reports how envKey was resolved - "flag", "env", "dotenv",
+// "file", or "default" - and whether envKey is a registered field at all.
+func (l *loader) origin(envKey string) (string, bool) {
+	for _, f := range l.fields {
+		if f.envKey == envKey {
+			return f.origin, true
+		}
+	}
+	return "", false
+}
+
+// setResolved overwrites envKey's raw value and origin after the fact, for
+// callers (detectManagedURLs) that derive a field's value from something
+// other than the layered sources parse already consulted. It reports
+// whether envKey is a registered field. Without this, validate and dump
+// would keep seeing whatever parse() resolved (often "", for a field with
+// no default) even once the caller has given the field a real value.
+func (l *loader) setResolved(envKey, raw, origin string) bool {
+	for _, f := range l.fields {
+		if f.envKey == envKey {
+			f.raw, f.origin = raw, origin
+			return true
+		}
+	}
+	return false
+}
+
+// setField converts raw into the kind of value and assigns it.
+func setField(value reflect.Value, raw string) error {
+	switch value.Interface().(type) {
+	case time.Duration:
+		if raw == "" {
+			return nil
+		}
+		d, err := parseDuration(raw)
+		if err != nil {
+			return err
+		}
+		value.Set(reflect.ValueOf(d))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if raw == "" {
+			return nil
+		}
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		value.SetInt(n)
+	case reflect.Bool:
+		if raw == "" {
+			return nil
+		}
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		value.SetBool(b)
+	default:
+		// Unsupported field kinds (e.g. slices) are left to the caller's
+		// own derived-value logic and are simply skipped here.
+	}
+
+	return nil
+}
+
+// parseDuration accepts both a Go duration string ("5s") and a bare
+// integer, which it treats as a count of milliseconds. The OTel spec
+// defines duration env vars like OTEL_BSP_SCHEDULE_DELAY as plain
+// integer milliseconds, not Go duration syntax, so a bare "5000" must
+// work the same way it does for every other OTel SDK/collector.
+func parseDuration(raw string) (time.Duration, error) {
+	if ms, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+	return time.ParseDuration(raw)
+}
+
+// ValidationErrors aggregates every constraint violation found by
+// Service.Validate, rather than surfacing only the first.
+type ValidationErrors []error
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// validate checks every registered field's `required` and `validate` tags,
+// returning a ValidationErrors aggregating all violations, or nil.
+func (l *loader) validate() error {
+	var errs ValidationErrors
+
+	for _, f := range l.fields {
+		if f.required && f.raw == "" {
+			errs = append(errs, fmt.Errorf("%s is required", f.envKey))
+			continue
+		}
+
+		if f.validate == "" || f.raw == "" {
+			continue
+		}
+
+		n, err := strconv.Atoi(f.raw)
+		if err != nil {
+			continue // validate currently only supports numeric min/max
+		}
+		for _, rule := range strings.Split(f.validate, ",") {
+			kv := strings.SplitN(rule, "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			bound, err := strconv.Atoi(kv[1])
+			if err != nil {
+				continue
+			}
+			switch kv[0] {
+			case "min":
+				if n < bound {
+					errs = append(errs, fmt.Errorf("%s: %d is below minimum %d", f.envKey, n, bound))
+				}
+			case "max":
+				if n > bound {
+					errs = append(errs, fmt.Errorf("%s: %d is above maximum %d", f.envKey, n, bound))
+				}
+			}
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// dump writes every registered field as "ENV_KEY=value (source: origin)"
+// (one per line), redacting fields tagged secret:"true".
+func (l *loader) dump(w io.Writer) {
+	for _, f := range l.fields {
+		value := f.raw
+		if f.secret && value != "" {
+			value = "***"
+		}
+		fmt.Fprintf(w, "%s=%s (source: %s)\n", f.envKey, value, f.origin)
+	}
+}
+
+// readConfigFile loads a YAML or JSON config file (chosen by extension)
+// into a flat key/value map. An empty path, or a missing file, yields an
+// empty map rather than an error since CONFIG_FILE is optional.
+func readConfigFile(path string) map[string]string {
+	values := make(map[string]string)
+	if path == "" {
+		return values
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return values
+	}
+
+	raw := make(map[string]interface{})
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &raw)
+	default:
+		err = yaml.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return values
+	}
+
+	for k, v := range raw {
+		values[k] = fmt.Sprintf("%v", v)
+	}
+	return values
+}
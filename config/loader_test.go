@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	cases := []struct {
+		raw  string
+		want time.Duration
+	}{
+		{"5000", 5000 * time.Millisecond}, // OTEL_BSP_SCHEDULE_DELAY style bare milliseconds
+		{"5s", 5 * time.Second},
+		{"250ms", 250 * time.Millisecond},
+	}
+
+	for _, c := range cases {
+		got, err := parseDuration(c.raw)
+		if err != nil {
+			t.Fatalf("parseDuration(%q) returned error: %v", c.raw, err)
+		}
+		if got != c.want {
+			t.Errorf("parseDuration(%q) = %v, want %v", c.raw, got, c.want)
+		}
+	}
+}
+
+func TestParseDurationInvalid(t *testing.T) {
+	if _, err := parseDuration("not-a-duration"); err == nil {
+		t.Fatal("parseDuration(\"not-a-duration\") expected an error, got nil")
+	}
+}
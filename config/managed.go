@@ -0,0 +1,125 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+)
+
+// redisManagedURLVars lists the environment variables that may carry a
+// full Redis connection URL, probed in this order. REDIS_URL is the
+// template's own variable; the rest are injected by various PaaS add-ons
+// (Heroku Redis Cloud/To Go, OpenRedis, RedisGreen, Boxen).
+var redisManagedURLVars = []string{
+	"REDIS_URL",
+	"REDISCLOUD_URL",
+	"REDISTOGO_URL",
+	"OPENREDIS_URL",
+	"REDISGREEN_URL",
+	"BOXEN_REDIS_URL",
+}
+
+// dbManagedURLVars lists the environment variables that may carry a full
+// Postgres/MySQL connection URL, probed in this order.
+var dbManagedURLVars = []string{
+	"DATABASE_URL",
+	"CLEARDB_DATABASE_URL",
+	"JAWSDB_URL",
+}
+
+// detectManagedURLs populates RedisConfig and DBConfig from a PaaS-injected
+// connection URL (Heroku, Render, Fly, Railway, ...) when one is present,
+// taking the first match from redisManagedURLVars/dbManagedURLVars. It
+// defers to discrete vars the user set explicitly (flag, env, .env, or
+// CONFIG_FILE - anything but the struct default) rather than silently
+// overriding them: a platform add-on shouldn't be able to override an
+// operator's own REDIS_HOST or DB_HOST. When no managed URL is set, or the
+// discrete vars win, the fields loaded by the tag loader stand unchanged.
+//
+// Overridden fields are recorded into sources so Source reports the
+// platform variable they came from, and also fed back into the loader via
+// setResolved so Validate sees them as present - otherwise a field like
+// REDIS_PASSWORD that only ever came from REDIS_URL's userinfo would still
+// read as unset (required:"true" checks f.raw, which parse() populated
+// before this ran) and fail validation despite GetRedisConfig() already
+// reflecting the right value.
+func (cnf *Service) detectManagedURLs(l *loader, sources map[string]string) {
+	for _, name := range redisManagedURLVars {
+		rawURL := getEnv(name, "")
+		if rawURL == "" {
+			continue
+		}
+		if explicitlySet(l, "REDIS_HOST") || explicitlySet(l, "REDIS_PORT") || getEnv("REDIS_ADDRS", "") != "" {
+			break
+		}
+		for key, raw := range applyRedisURL(&cnf.redisEnv, rawURL) {
+			sources[key] = "platform:" + name
+			l.setResolved(key, raw, "platform:"+name)
+		}
+		break
+	}
+
+	for _, name := range dbManagedURLVars {
+		rawURL := getEnv(name, "")
+		if rawURL == "" {
+			continue
+		}
+		if explicitlySet(l, "DB_HOST") || explicitlySet(l, "DB_PORT") {
+			break
+		}
+		for key, raw := range applyDatabaseURL(&cnf.dbEnv, rawURL) {
+			sources[key] = "platform:" + name
+			l.setResolved(key, raw, "platform:"+name)
+		}
+		break
+	}
+}
+
+// explicitlySet reports whether envKey was resolved from a flag, the
+// environment, the .env file, or CONFIG_FILE - as opposed to its struct
+// default, or not being a registered field at all.
+func explicitlySet(l *loader, envKey string) bool {
+	origin, ok := l.origin(envKey)
+	return ok && origin != "default"
+}
+
+// applyDatabaseURL overrides cfg with the host, port, userinfo, and
+// database name of a postgres://, postgresql://, or mysql:// URL, as
+// injected by DATABASE_URL/CLEARDB_DATABASE_URL/JAWSDB_URL. It returns the
+// env keys the URL actually carried a value for, mapped to that value, so
+// the caller can keep the loader's view of those fields (raw/origin, used
+// by Validate and Source) in sync with the override.
+func applyDatabaseURL(cfg *DBConfig, rawURL string) map[string]string {
+	set := make(map[string]string)
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return set
+	}
+
+	if host := u.Hostname(); host != "" {
+		cfg.Host = host
+		set["DB_HOST"] = host
+	}
+	if port := u.Port(); port != "" {
+		cfg.Port = port
+		set["DB_PORT"] = port
+	}
+
+	if u.User != nil {
+		if username := u.User.Username(); username != "" {
+			cfg.User = username
+			set["DB_USER"] = username
+		}
+		if password, ok := u.User.Password(); ok {
+			cfg.Password = password
+			set["DB_PASSWORD"] = password
+		}
+	}
+
+	if name := strings.TrimPrefix(u.Path, "/"); name != "" {
+		cfg.Name = name
+		set["DB_NAME"] = name
+	}
+
+	return set
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// parseRedisAddrs reads REDIS_ADDRS (a comma-separated list of host:port
+// pairs) and falls back to the single host:port pair already resolved onto
+// RedisConfig.Host/Port (REDIS_HOST/REDIS_PORT) by the tag loader.
+func parseRedisAddrs(host, port string) []string {
+	if raw := getEnv("REDIS_ADDRS", ""); raw != "" {
+		addrs := make([]string, 0)
+		for _, addr := range strings.Split(raw, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				addrs = append(addrs, addr)
+			}
+		}
+		if len(addrs) > 0 {
+			return addrs
+		}
+	}
+
+	return []string{host + ":" + port}
+}
+
+// applyRedisURL overrides cfg with the scheme, userinfo, host, and path of a
+// redis:// or rediss:// URL. rediss:// enables TLS. It returns the env keys
+// the URL actually carried a value for, mapped to that value, so the
+// caller can keep the loader's view of those fields (raw/origin, used by
+// Validate and Source) in sync with the override.
+func applyRedisURL(cfg *RedisConfig, rawURL string) map[string]string {
+	set := make(map[string]string)
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return set
+	}
+
+	cfg.Addresses = []string{u.Host}
+	cfg.TLSEnabled = u.Scheme == "rediss"
+	cfg.Host = u.Hostname()
+	set["REDIS_HOST"] = cfg.Host
+	if port := u.Port(); port != "" {
+		cfg.Port = port
+		set["REDIS_PORT"] = port
+	}
+
+	if u.User != nil {
+		if username := u.User.Username(); username != "" {
+			cfg.Username = username
+			set["REDIS_USERNAME"] = username
+		}
+		if password, ok := u.User.Password(); ok {
+			cfg.Password = password
+			set["REDIS_PASSWORD"] = password
+		}
+	}
+
+	if dbStr := strings.TrimPrefix(u.Path, "/"); dbStr != "" {
+		if db, err := strconv.Atoi(dbStr); err == nil {
+			cfg.DB = db
+			set["REDIS_DB"] = dbStr
+		}
+	}
+
+	return set
+}
+
+// NewUniversalClient builds a redis.UniversalClient whose topology is
+// chosen explicitly from Mode ("single", "sentinel", or "cluster") rather
+// than inferred from the shape of Addresses/MasterName the way
+// redis.NewUniversalClient does. That inference gets single-endpoint
+// managed Redis Cluster offerings wrong: REDIS_MODE=cluster with one seed
+// address would otherwise silently connect as a plain single-node client
+// instead of a cluster client, with no error - just the wrong topology and
+// eventual MOVED-redirection failures at runtime.
+func (r RedisConfig) NewUniversalClient() (redis.UniversalClient, error) {
+	opts, err := r.BuildUniversalOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	switch r.Mode {
+	case "", "single":
+		return redis.NewClient(opts.Simple()), nil
+	case "sentinel":
+		return redis.NewFailoverClient(opts.Failover()), nil
+	case "cluster":
+		return redis.NewClusterClient(opts.Cluster()), nil
+	default:
+		return nil, fmt.Errorf("redis: unknown REDIS_MODE %q (want single, sentinel, or cluster)", r.Mode)
+	}
+}
+
+// BuildUniversalOptions translates RedisConfig into *redis.UniversalOptions,
+// the single representation NewUniversalClient's Simple/Failover/Cluster
+// accessors narrow to the options struct each concrete client constructor
+// wants. It returns an error if TLSEnabled is set but the configured cert,
+// key, or CA file can't be loaded, rather than silently connecting without
+// them.
+func (r RedisConfig) BuildUniversalOptions() (*redis.UniversalOptions, error) {
+	opts := &redis.UniversalOptions{
+		Addrs:            r.Addresses,
+		MasterName:       r.MasterName,
+		Username:         r.Username,
+		Password:         r.Password,
+		SentinelUsername: r.SentinelUsername,
+		SentinelPassword: r.SentinelPassword,
+		DB:               r.DB,
+		RouteByLatency:   r.RouteByLatency,
+		RouteRandomly:    r.RouteRandomly,
+		PoolSize:         r.PoolSize,
+		MinIdleConns:     r.MinIdleConns,
+		ReadTimeout:      r.ReadTimeout,
+		WriteTimeout:     r.WriteTimeout,
+		DialTimeout:      r.DialTimeout,
+	}
+
+	if r.TLSEnabled {
+		tlsConfig, err := buildTLSConfig(r.TLSCertFile, r.TLSKeyFile, r.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("redis: %w", err)
+		}
+		opts.TLSConfig = tlsConfig
+	}
+
+	return opts, nil
+}
+
+// buildTLSConfig loads a client TLS configuration from the given cert, key,
+// and CA file paths. Any of them may be empty, in which case the system
+// root CAs and no client certificate are used - but a path that is set and
+// fails to load is an error, not a silent fallback to an unauthenticated
+// connection. The cert and key go together: one set without the other is
+// an error.
+func buildTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no valid certificates found in CA file %s", caFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	return cfg, nil
+}
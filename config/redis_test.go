@@ -0,0 +1,66 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestNewUniversalClientRespectsMode(t *testing.T) {
+	cases := []struct {
+		mode string
+		addr string
+		want string // expected concrete type, via a type switch below
+	}{
+		{"single", "localhost:6379", "*redis.Client"},
+		{"", "localhost:6379", "*redis.Client"},
+		{"sentinel", "localhost:26379", "*redis.Client"}, // NewFailoverClient also returns *redis.Client
+		{"cluster", "localhost:7000", "*redis.ClusterClient"},
+	}
+
+	for _, c := range cases {
+		cfg := RedisConfig{Mode: c.mode, Addresses: []string{c.addr}, MasterName: "mymaster"}
+		client, err := cfg.NewUniversalClient()
+		if err != nil {
+			t.Fatalf("Mode %q: NewUniversalClient: %v", c.mode, err)
+		}
+		defer client.Close()
+
+		switch client.(type) {
+		case *redis.ClusterClient:
+			if c.want != "*redis.ClusterClient" {
+				t.Errorf("Mode %q: got *redis.ClusterClient, want %s", c.mode, c.want)
+			}
+		case *redis.Client:
+			if c.want != "*redis.Client" {
+				t.Errorf("Mode %q: got *redis.Client, want %s", c.mode, c.want)
+			}
+		default:
+			t.Errorf("Mode %q: unexpected client type %T", c.mode, client)
+		}
+	}
+}
+
+func TestNewUniversalClientRejectsUnknownMode(t *testing.T) {
+	cfg := RedisConfig{Mode: "bogus", Addresses: []string{"localhost:6379"}}
+	if _, err := cfg.NewUniversalClient(); err == nil {
+		t.Fatal("expected an error for an unknown REDIS_MODE, got nil")
+	}
+}
+
+// TestNewUniversalClientForcesClusterWithOneAddress reproduces the bug
+// REDIS_MODE exists to prevent: a managed Redis Cluster offering that
+// exposes a single configuration endpoint must still produce a cluster
+// client, not a single-node client silently chosen because len(Addrs) == 1.
+func TestNewUniversalClientForcesClusterWithOneAddress(t *testing.T) {
+	cfg := RedisConfig{Mode: "cluster", Addresses: []string{"cluster-endpoint:6379"}}
+	client, err := cfg.NewUniversalClient()
+	if err != nil {
+		t.Fatalf("NewUniversalClient: %v", err)
+	}
+	defer client.Close()
+
+	if _, ok := client.(*redis.ClusterClient); !ok {
+		t.Fatalf("got %T, want *redis.ClusterClient even with a single seed address", client)
+	}
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"log"
+	"strings"
+	"time"
+)
+
+// TelemetryConfig configures exporting traces over OTLP (gRPC or HTTP) to
+// any OTel-compatible backend (Grafana Tempo, Honeycomb, Tencent APM, and
+// the like).
+//
+// It supersedes JaegerConfig, which only spoke the deprecated Jaeger agent
+// UDP protocol. Setting TELEMETRY_LEGACY_JAEGER=true keeps using the Jaeger
+// agent exporter for one release; LoadConfig logs a deprecation warning
+// when that flag is set.
+type TelemetryConfig struct {
+	// Endpoint is the OTLP collector address, e.g. "localhost:4317" for
+	// gRPC or "localhost:4318" for HTTP.
+	Endpoint string `env:"OTEL_EXPORTER_OTLP_ENDPOINT" default:"localhost:4317"`
+	// Protocol selects the wire format: "grpc" or "http/protobuf".
+	Protocol string `env:"OTEL_EXPORTER_OTLP_PROTOCOL" default:"grpc"`
+
+	// HeadersRaw is "k=v,k2=v2"; parsed into Headers by LoadConfig. Used to
+	// carry auth tokens required by some APM vendors.
+	HeadersRaw string `env:"OTEL_EXPORTER_OTLP_HEADERS" secret:"true"`
+	Headers    map[string]string
+
+	ServiceName string `env:"OTEL_SERVICE_NAME" default:"go-rest-api-template"`
+
+	// ResourceAttributesRaw is "k=v,k2=v2"; parsed into ResourceAttributes
+	// by LoadConfig.
+	ResourceAttributesRaw string `env:"OTEL_RESOURCE_ATTRIBUTES"`
+	ResourceAttributes     map[string]string
+
+	// Sampler is "always_on" or "parentbased_traceidratio"; SamplerArg is
+	// the sampling ratio used by the latter.
+	Sampler    string `env:"OTEL_TRACES_SAMPLER" default:"parentbased_traceidratio"`
+	SamplerArg string `env:"OTEL_TRACES_SAMPLER_ARG" default:"1.0"`
+
+	TLSEnabled  bool   `env:"OTEL_EXPORTER_OTLP_TLS_ENABLED"`
+	TLSCertFile string `env:"OTEL_EXPORTER_OTLP_TLS_CERT_FILE"`
+	TLSKeyFile  string `env:"OTEL_EXPORTER_OTLP_TLS_KEY_FILE"`
+	TLSCAFile   string `env:"OTEL_EXPORTER_OTLP_TLS_CA_FILE"`
+
+	// BatchTimeout is OTEL_BSP_SCHEDULE_DELAY, which the OTel spec defines
+	// as a bare integer count of milliseconds (e.g. "5000"), not Go
+	// duration syntax - loader.parseDuration accepts both.
+	BatchTimeout       time.Duration `env:"OTEL_BSP_SCHEDULE_DELAY" default:"5000"`
+	MaxQueueSize       int           `env:"OTEL_BSP_MAX_QUEUE_SIZE" default:"2048"`
+	MaxExportBatchSize int           `env:"OTEL_BSP_MAX_EXPORT_BATCH_SIZE" default:"512"`
+
+	// LegacyJaeger, when true, falls back to exporting via the deprecated
+	// Jaeger agent UDP protocol (see JaegerConfig) instead of OTLP.
+	LegacyJaeger bool `env:"TELEMETRY_LEGACY_JAEGER"`
+}
+
+// warnIfLegacyJaeger logs a deprecation warning when the legacy Jaeger
+// agent exporter has been requested, pointing callers at OTLP.
+func warnIfLegacyJaeger(cfg TelemetryConfig) {
+	if cfg.LegacyJaeger {
+		log.Printf("config: TELEMETRY_LEGACY_JAEGER is deprecated and will be removed in a future release; migrate to OTEL_EXPORTER_OTLP_ENDPOINT")
+	}
+}
+
+// parseKV parses a "k=v,k2=v2" string into a map, as used by both
+// OTEL_EXPORTER_OTLP_HEADERS and OTEL_RESOURCE_ATTRIBUTES.
+func parseKV(raw string) map[string]string {
+	values := make(map[string]string)
+	if raw == "" {
+		return values
+	}
+	for _, pair := range strings.Split(raw, ",") {
+		kv := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		values[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return values
+}
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Snapshot is an immutable copy of every subsection of the application
+// configuration at a point in time. Watch delivers a Snapshot to every
+// Subscribe channel and every registered Reloadable whenever the
+// underlying configuration changes.
+type Snapshot struct {
+	DB        DBConfig
+	Redis     RedisConfig
+	Server    ServerConf
+	Jaeger    JaegerConfig
+	Telemetry TelemetryConfig
+}
+
+// Reloadable is implemented by subsystems that can apply a new Snapshot
+// without a process restart - a Redis client pool resizing, a log level
+// manager, a trace sampler adjusting its ratio.
+type Reloadable interface {
+	Reload(Snapshot) error
+}
+
+// subscriber is a named Subscribe channel.
+type subscriber struct {
+	name string
+	ch   chan Snapshot
+}
+
+// Register adds a Reloadable to be notified, in registration order, of
+// every configuration change picked up by Watch.
+func (cnf *Service) Register(r Reloadable) {
+	cnf.mu.Lock()
+	defer cnf.mu.Unlock()
+	cnf.reloadables = append(cnf.reloadables, r)
+}
+
+// Subscribe returns a channel that receives the new Snapshot every time
+// Watch reloads the configuration and finds it changed. name is recorded
+// with the subscription, need not be unique, and is not otherwise used.
+// The channel is buffered by one; a subscriber that hasn't drained the previous value
+// before the next reload misses the stale one, not the current one. The
+// channel is closed when the context passed to Watch is done.
+func (cnf *Service) Subscribe(name string) <-chan Snapshot {
+	ch := make(chan Snapshot, 1)
+
+	cnf.mu.Lock()
+	cnf.subscribers = append(cnf.subscribers, subscriber{name: name, ch: ch})
+	cnf.mu.Unlock()
+
+	return ch
+}
+
+// Watch watches the .env file and CONFIG_FILE (if set) for changes via
+// fsnotify, and reloads on SIGHUP, until ctx is done. Each reload re-runs
+// LoadConfig and diffs the result against the previous Snapshot; if
+// nothing actually changed, no one is notified. Otherwise every Subscribe
+// channel and every Reloadable registered via Register receives the new
+// Snapshot.
+//
+// It watches the containing directory of each file, not the file itself,
+// and filters events by basename. A file-level watch is tied to that
+// file's inode, so an atomic rename-over-existing save (vim's default
+// write, and the symlink-swap Kubernetes uses for mounted ConfigMaps/
+// Secrets) fires a single Remove event and then delivers nothing ever
+// again for the rest of the process, since the replaced file is a new
+// inode the watch never picked up. A directory's inode survives those
+// swaps, so the watch keeps working across any number of them.
+func (cnf *Service) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("config: watch: %w", err)
+	}
+	defer watcher.Close()
+
+	watched := make(map[string]bool) // basename -> watch this file's events
+	dirs := make(map[string]bool)    // directory -> already added to watcher
+	for _, path := range []string{".env", getEnv("CONFIG_FILE", "")} {
+		if path == "" {
+			continue
+		}
+		watched[filepath.Base(path)] = true
+
+		dir := filepath.Dir(path)
+		if dirs[dir] {
+			continue
+		}
+		dirs[dir] = true
+		if err := watcher.Add(dir); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("config: watch %s: %w", dir, err)
+		}
+	}
+
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	defer signal.Stop(sighup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			cnf.closeSubscribers()
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !watched[filepath.Base(event.Name)] {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) == 0 {
+				continue
+			}
+			if err := cnf.reload(); err != nil {
+				return err
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("config: watch: %w", err)
+
+		case <-sighup:
+			if err := cnf.reload(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// reload re-runs LoadConfig, then notifies subscribers and reloadables
+// with the new Snapshot only if at least one subsection actually changed.
+func (cnf *Service) reload() error {
+	prev := cnf.snap.Load()
+
+	if err := cnf.LoadConfig(); err != nil {
+		return fmt.Errorf("config: reload: %w", err)
+	}
+	next := cnf.snap.Load()
+
+	if !snapshotChanged(prev, next) {
+		return nil
+	}
+
+	cnf.mu.Lock()
+	subs := append([]subscriber(nil), cnf.subscribers...)
+	reloadables := append([]Reloadable(nil), cnf.reloadables...)
+	cnf.mu.Unlock()
+
+	for _, sub := range subs {
+		select {
+		case sub.ch <- *next:
+		default:
+			// Drop the stale pending value and deliver the latest instead.
+			select {
+			case <-sub.ch:
+			default:
+			}
+			sub.ch <- *next
+		}
+	}
+
+	for _, r := range reloadables {
+		if err := r.Reload(*next); err != nil {
+			return fmt.Errorf("config: reload: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// snapshotChanged reports whether any subsection differs between prev and
+// next, so a reload that resolved to the exact same values is a no-op.
+func snapshotChanged(prev, next *Snapshot) bool {
+	if prev == nil || next == nil {
+		return prev != next
+	}
+	return !reflect.DeepEqual(prev.DB, next.DB) ||
+		!reflect.DeepEqual(prev.Redis, next.Redis) ||
+		!reflect.DeepEqual(prev.Server, next.Server) ||
+		!reflect.DeepEqual(prev.Jaeger, next.Jaeger) ||
+		!reflect.DeepEqual(prev.Telemetry, next.Telemetry)
+}
+
+// closeSubscribers closes every Subscribe channel, signaling subscribers
+// that Watch has stopped.
+func (cnf *Service) closeSubscribers() {
+	cnf.mu.Lock()
+	defer cnf.mu.Unlock()
+	for _, sub := range cnf.subscribers {
+		close(sub.ch)
+	}
+	cnf.subscribers = nil
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestConcurrentLoadAndInspect exercises LoadConfig racing against
+// Validate/Dump/Source, the pattern Watch's background reload loop uses
+// against callers inspecting the Service from other goroutines. Run with
+// `go test -race` to catch regressions where loader/sources are read
+// without going through their atomic.Pointer.
+func TestConcurrentLoadAndInspect(t *testing.T) {
+	cnf := NewService()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			if err := cnf.LoadConfig(); err != nil {
+				t.Errorf("LoadConfig: %v", err)
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			_ = cnf.Validate()
+			cnf.Dump(io.Discard)
+			_ = cnf.Source("DB_HOST")
+		}
+	}()
+
+	wg.Wait()
+}
+
+// TestWatchSurvivesAtomicRename reproduces vim's default write (and the
+// ConfigMap/Secret symlink-swap Kubernetes uses): the .env file is replaced
+// by renaming a temp file over it rather than written in place. A watch
+// tied to the old file's inode would deliver one Remove event and then
+// nothing ever again; Watch must keep delivering updates afterward because
+// it watches the containing directory instead.
+func TestWatchSurvivesAtomicRename(t *testing.T) {
+	dir := t.TempDir()
+	restore := chdir(t, dir)
+	defer restore()
+
+	if err := os.WriteFile(".env", []byte("DB_HOST=first-host\n"), 0o644); err != nil {
+		t.Fatalf("write .env: %v", err)
+	}
+
+	cnf := NewService()
+	if err := cnf.Init(); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	ch := cnf.Subscribe("test")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- cnf.Watch(ctx) }()
+
+	// Give fsnotify a moment to register the directory watch before the
+	// first rename, then replace .env the same way vim/Kubernetes do:
+	// write the new content to a sibling temp file and rename it over the
+	// target, rather than writing the target in place.
+	time.Sleep(100 * time.Millisecond)
+	atomicWrite(t, ".env", "DB_HOST=second-host\n")
+
+	select {
+	case snap := <-ch:
+		if snap.DB.Host != "second-host" {
+			t.Fatalf("Snapshot.DB.Host = %q, want second-host", snap.DB.Host)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the first atomic rename to be picked up")
+	}
+
+	// A second rename must also be picked up - this is exactly what a
+	// file-level (rather than directory-level) watch fails to do, since
+	// the watch was never re-added after the first rename destroyed the
+	// inode it was attached to.
+	atomicWrite(t, ".env", "DB_HOST=third-host\n")
+
+	select {
+	case snap := <-ch:
+		if snap.DB.Host != "third-host" {
+			t.Fatalf("Snapshot.DB.Host = %q, want third-host", snap.DB.Host)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the second atomic rename to be picked up")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Watch returned %v, want nil on context cancellation", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch did not return after ctx was canceled")
+	}
+}
+
+// atomicWrite replaces path's content by writing to a sibling temp file and
+// renaming it over path, the same pattern vim and Kubernetes use.
+func atomicWrite(t *testing.T, path, content string) {
+	t.Helper()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+		t.Fatalf("write %s: %v", tmp, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatalf("rename %s -> %s: %v", tmp, path, err)
+	}
+}
+
+// chdir changes the working directory to dir and returns a func that
+// restores the previous one.
+func chdir(t *testing.T, dir string) func() {
+	t.Helper()
+	prev, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		t.Fatalf("abs: %v", err)
+	}
+	if err := os.Chdir(abs); err != nil {
+		t.Fatalf("chdir %s: %v", abs, err)
+	}
+	return func() {
+		if err := os.Chdir(prev); err != nil {
+			t.Fatalf("chdir back to %s: %v", prev, err)
+		}
+	}
+}